@@ -0,0 +1,58 @@
+package main
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"net/http"
+
+	log "github.com/sirupsen/logrus"
+)
+
+// currentPayload returns the PayloadData to serve right now: the cached payload in
+// LANDER_WATCH mode, or a fresh discovery run otherwise. This mirrors RenderAndRespond so the
+// JSON and HTML endpoints always agree.
+func currentPayload() PayloadData {
+	if RuntimeConfig.Watch == "true" {
+		payloadCacheMu.RLock()
+		defer payloadCacheMu.RUnlock()
+		return payloadCache
+	}
+
+	return PayloadData{RuntimeConfig.Title, groupContainers(discoverAll(activeProviders))}
+}
+
+// payloadETag computes an ETag from the JSON-encoded payload so clients can conditionally
+// fetch with If-None-Match instead of re-downloading an unchanged payload.
+func payloadETag(body []byte) string {
+	sum := sha256.Sum256(body)
+	return `"` + hex.EncodeToString(sum[:]) + `"`
+}
+
+// ServeGroupsJSON handles GET /api/v1/groups, serving the same PayloadData RenderAndRespond
+// renders as HTML, but as JSON for external dashboards or scripts to consume directly.
+func ServeGroupsJSON(w http.ResponseWriter, r *http.Request) {
+	log.Debug(r.RemoteAddr, " ", r.Method, " ", r.URL)
+
+	if RuntimeConfig.CORSOrigin != "" {
+		w.Header().Set("Access-Control-Allow-Origin", RuntimeConfig.CORSOrigin)
+	}
+
+	body, err := json.Marshal(currentPayload())
+	if err != nil {
+		log.Error(err)
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	etag := payloadETag(body)
+	w.Header().Set("ETag", etag)
+
+	if r.Header.Get("If-None-Match") == etag {
+		w.WriteHeader(http.StatusNotModified)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.Write(body)
+}
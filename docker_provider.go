@@ -0,0 +1,135 @@
+package main
+
+import (
+	"errors"
+	"strings"
+
+	"github.com/cenkalti/backoff"
+	"github.com/fsouza/go-dockerclient"
+	log "github.com/sirupsen/logrus"
+)
+
+// DockerProvider discovers Containers from a docker daemon's container labels: Traefik router
+// labels when present, falling back to directly exposed ports.
+type DockerProvider struct {
+	Endpoint string // docker's api endpoint, e.g. unix:///var/run/docker.sock
+}
+
+// newDockerClient builds a docker client for the given endpoint. If any of
+// LANDER_DOCKER_TLS_CA, LANDER_DOCKER_TLS_CERT or LANDER_DOCKER_TLS_KEY is set it builds a TLS
+// client (LANDER_DOCKER_TLS_INSECURE alone does not) so lander can talk to a
+// remote docker daemon (or swarm manager) over the network instead of the local socket.
+func newDockerClient(endpoint string) (*docker.Client, error) {
+	if RuntimeConfig.DockerTLSCA == "" && RuntimeConfig.DockerTLSCert == "" && RuntimeConfig.DockerTLSKey == "" {
+		return docker.NewClient(endpoint)
+	}
+
+	client, err := docker.NewTLSClient(endpoint, RuntimeConfig.DockerTLSCert, RuntimeConfig.DockerTLSKey, RuntimeConfig.DockerTLSCA)
+	if err != nil {
+		return nil, err
+	}
+
+	if RuntimeConfig.DockerTLSInsecure == "true" {
+		client.TLSConfig.InsecureSkipVerify = true
+	}
+
+	return client, nil
+}
+
+// Discover lists all containers on the configured docker endpoint and resolves their lander
+// labels into Containers.
+func (p DockerProvider) Discover() ([]Container, error) {
+	client, err := newDockerClient(p.Endpoint)
+	if err != nil {
+		return nil, err
+	}
+
+	apiContainers, err := client.ListContainers(docker.ListContainersOptions{All: true})
+	if err != nil {
+		return nil, err
+	}
+
+	return resolveContainers(apiContainers), nil
+}
+
+// Watch keeps pushing a refreshed container list to updates whenever docker reports a
+// start/die/destroy/health_status event. It pushes once immediately so callers get an initial
+// list before the first event. Transient docker socket disconnects are retried with
+// exponential backoff instead of crashing the process.
+func (p DockerProvider) Watch(updates chan<- []Container) {
+	for {
+		operation := func() error {
+			client, err := newDockerClient(p.Endpoint)
+			if err != nil {
+				return err
+			}
+
+			events := make(chan *docker.APIEvents)
+			if err := client.AddEventListener(events); err != nil {
+				return err
+			}
+			defer client.RemoveEventListener(events)
+
+			containers, err := p.Discover()
+			if err != nil {
+				return err
+			}
+			updates <- containers
+
+			for event := range events {
+				if event.Type != "" && event.Type != "container" {
+					continue
+				}
+
+				switch event.Action {
+				case "start", "die", "destroy":
+				default:
+					if !strings.HasPrefix(event.Action, "health_status") {
+						continue
+					}
+				}
+
+				log.Debug("docker event triggered refresh: ", event.Action, " ", event.ID)
+
+				containers, err := p.Discover()
+				if err != nil {
+					return err
+				}
+				updates <- containers
+			}
+
+			return errors.New("docker event stream closed")
+		}
+
+		if err := backoff.Retry(operation, backoff.NewExponentialBackOff()); err != nil {
+			log.Error("docker event watcher gave up, restarting: ", err)
+		}
+	}
+}
+
+// resolveContainers turns raw docker containers carrying a "lander.enable" label into
+// Containers, consulting Traefik labels first and falling back to exposed ports.
+func resolveContainers(apiContainers []docker.APIContainers) []Container {
+	var containers []Container
+
+	for _, apiContainer := range apiContainers {
+		if _, found := apiContainer.Labels["lander.enable"]; !found {
+			continue
+		}
+
+		log.Debug("found lander labels on Container: ", apiContainer.ID)
+
+		// Traefik takes priority; Exposed is only consulted when Traefik found nothing
+		found, err := GetTraefikConfiguration(apiContainer)
+		if err != nil {
+			found, err = GetExposedConfiguration(apiContainer)
+			if err != nil {
+				continue
+			}
+		}
+
+		containers = append(containers, found...)
+	}
+
+	return containers
+}
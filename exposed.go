@@ -0,0 +1,57 @@
+package main
+
+import (
+	"errors"
+	"strconv"
+
+	"github.com/fsouza/go-dockerclient"
+)
+
+// GetExposedConfiguration builds a Container from a container's published ports. It's the
+// fallback for hosts that expose services directly on the docker host instead of routing them
+// through Traefik, used when LANDER_EXPOSED is enabled.
+func GetExposedConfiguration(container docker.APIContainers) ([]Container, error) {
+	if RuntimeConfig.Exposed != "true" {
+		return nil, errors.New("LANDER_EXPOSED is set to false")
+	}
+
+	port, err := selectExposedPort(container)
+	if err != nil {
+		return nil, err
+	}
+
+	scheme := "http"
+	if container.Labels["lander.scheme"] == "https" {
+		scheme = "https"
+	}
+
+	containerName := container.Labels["lander.name"]
+	group := container.Labels["lander.group"]
+
+	return []Container{{
+		AppName: containerName,
+		AppURL:  scheme + "://" + RuntimeConfig.Hostname + ":" + strconv.FormatInt(port, 10),
+		Group:   group,
+	}}, nil
+}
+
+// selectExposedPort picks the published port to advertise for a container. The lander.port
+// label, when set, picks a specific container port out of several exposed ones; otherwise the
+// first port with a public mapping is used.
+func selectExposedPort(container docker.APIContainers) (int64, error) {
+	wantPort := container.Labels["lander.port"]
+
+	for _, port := range container.Ports {
+		if port.PublicPort == 0 {
+			continue
+		}
+
+		if wantPort != "" && strconv.FormatInt(port.PrivatePort, 10) != wantPort {
+			continue
+		}
+
+		return port.PublicPort, nil
+	}
+
+	return 0, errors.New("no exposed port found")
+}
@@ -0,0 +1,56 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+
+	"github.com/BurntSushi/toml"
+	"gopkg.in/yaml.v2"
+)
+
+// FileProvider reads a static list of Containers from a TOML or YAML file, for services that
+// run on the same host without a container runtime lander can introspect.
+type FileProvider struct {
+	Path string // path to the TOML or YAML file to read, format picked from its extension
+}
+
+// fileProviderEntry mirrors a single entry in the static provider file.
+type fileProviderEntry struct {
+	Name  string `toml:"name" yaml:"name"`
+	URL   string `toml:"url" yaml:"url"`
+	Group string `toml:"group" yaml:"group"`
+}
+
+// fileProviderDocument is the root of the static provider file: a list of entries under
+// "containers".
+type fileProviderDocument struct {
+	Containers []fileProviderEntry `toml:"containers" yaml:"containers"`
+}
+
+// Discover reads FileProvider.Path and turns its entries into Containers. The format is picked
+// from the file extension: .yaml/.yml is parsed as YAML, anything else as TOML.
+func (p FileProvider) Discover() ([]Container, error) {
+	raw, err := os.ReadFile(p.Path)
+	if err != nil {
+		return nil, err
+	}
+
+	var doc fileProviderDocument
+
+	switch filepath.Ext(p.Path) {
+	case ".yaml", ".yml":
+		err = yaml.Unmarshal(raw, &doc)
+	default:
+		err = toml.Unmarshal(raw, &doc)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	containers := make([]Container, 0, len(doc.Containers))
+	for _, entry := range doc.Containers {
+		containers = append(containers, Container{AppName: entry.Name, AppURL: entry.URL, Group: entry.Group})
+	}
+
+	return containers, nil
+}
@@ -0,0 +1,69 @@
+package main
+
+import (
+	"context"
+	"fmt"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/rest"
+)
+
+// KubernetesProvider discovers Containers from networking.k8s.io/v1 Ingress objects, using the
+// in-cluster service account so lander needs no kubeconfig when it runs as a pod.
+type KubernetesProvider struct {
+	clientset *kubernetes.Clientset
+}
+
+// NewKubernetesProvider builds a KubernetesProvider from the pod's in-cluster configuration.
+func NewKubernetesProvider() (*KubernetesProvider, error) {
+	config, err := rest.InClusterConfig()
+	if err != nil {
+		return nil, fmt.Errorf("kubernetes in-cluster config: %w", err)
+	}
+
+	clientset, err := kubernetes.NewForConfig(config)
+	if err != nil {
+		return nil, fmt.Errorf("kubernetes client: %w", err)
+	}
+
+	return &KubernetesProvider{clientset: clientset}, nil
+}
+
+// Discover lists every Ingress across all namespaces and turns each rule/path into a Container,
+// grouped by namespace unless overridden with the lander.group annotation.
+func (p *KubernetesProvider) Discover() ([]Container, error) {
+	ingresses, err := p.clientset.NetworkingV1().Ingresses("").List(context.Background(), metav1.ListOptions{})
+	if err != nil {
+		return nil, err
+	}
+
+	var containers []Container
+	for _, ingress := range ingresses.Items {
+		group := ingress.Namespace
+		if override, found := ingress.Annotations["lander.group"]; found {
+			group = override
+		}
+
+		scheme := "http"
+		if len(ingress.Spec.TLS) > 0 {
+			scheme = "https"
+		}
+
+		for _, rule := range ingress.Spec.Rules {
+			if rule.HTTP == nil {
+				continue
+			}
+
+			for _, path := range rule.HTTP.Paths {
+				containers = append(containers, Container{
+					AppName: ingress.Name,
+					AppURL:  scheme + "://" + rule.Host + path.Path,
+					Group:   group,
+				})
+			}
+		}
+	}
+
+	return containers, nil
+}
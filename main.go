@@ -2,29 +2,37 @@
 package main
 
 import (
-	"errors"
-	"github.com/fsouza/go-dockerclient"
 	log "github.com/sirupsen/logrus"
 	"html/template"
 	"net/http"
 	"os"
-	"strings"
+	"sync"
 )
 
 // Type Config stores all configuration needed for lander
 type Config struct {
-	Traefik  string // should be a bool, but for now it's okay the way it is; determines whether lander searches for traefik labels. Default: true
-	Exposed  string // should be a bool, but for now it's okay the way it is; determines whether lander searches for exposed ports. Default: false
-	Listen   string // the ip and port on which lander will listen in the format <IP>:PORT. Default: :8080
-	Title    string // the title displayed on top of the default template header. Default: LANDER
-	Hostname string // the hostname of the host machine, used to create hyperlinks. Default: ""
-	Docker   string // path to docker's api endpoint (e.g. unix:///var/run/docker.sock)
+	Traefik    string // should be a bool, but for now it's okay the way it is; determines whether lander searches for traefik labels. Default: true
+	Exposed    string // should be a bool, but for now it's okay the way it is; determines whether lander searches for exposed ports. Default: false
+	Listen     string // the ip and port on which lander will listen in the format <IP>:PORT. Default: :8080
+	Title      string // the title displayed on top of the default template header. Default: LANDER
+	Hostname   string // the hostname of the host machine, used to create hyperlinks. Default: ""
+	Docker     string // path to docker's api endpoint (e.g. unix:///var/run/docker.sock)
+	Watch      string // should be a bool, but for now it's okay the way it is; determines whether lander watches for changes instead of polling on every request. Default: false
+	Providers  string // comma-separated list of providers to discover containers from, e.g. "docker,kubernetes,file". Default: docker
+	FilePath   string // path to the static TOML/YAML file read by the file provider
+	CORSOrigin string // value of the Access-Control-Allow-Origin header sent by /api/v1/groups. Default: "" (no CORS headers sent)
+
+	DockerTLSCA       string // path to the CA used to verify the docker daemon's certificate; setting any of DockerTLSCA/DockerTLSCert/DockerTLSKey enables TLS
+	DockerTLSCert     string // path to the client certificate used to authenticate against a remote docker daemon
+	DockerTLSKey      string // path to the client key used to authenticate against a remote docker daemon
+	DockerTLSInsecure string // should be a bool, but for now it's okay the way it is; skips verification of the docker daemon's certificate. Default: false
 }
 
-// Type Container stores the name of the application (running in an container) and the corresponding url
+// Type Container stores the name of the application, its url and the group it belongs to
 type Container struct {
 	AppName string // name of the application. Will be displayed as link title in the rendered template
 	AppURL  string // url (or better the context) of the application. Will be used to create hyperlinks
+	Group   string // the group this container belongs to, used to group applications in the rendered template/for headers of the html table rows
 }
 
 // Type PayloadData holds the title of the future index.html and a map of slices of struct Container
@@ -35,63 +43,17 @@ type PayloadData struct {
 
 var RuntimeConfig Config
 
-// Get is a method on variables from type PayloadData which gets all available metadata.
-func (payload PayloadData) Get(containers []docker.APIContainers) {
-	// iterate through slice of containers and find "lander" labels
-	for _, container := range containers {
-		// check if map contains a key named "lander.enable"
-		if _, found := container.Labels["lander.enable"]; found {
-			// give debug messages
-			log.Debug("found lander labels on Container: ", container.ID)
-
-			containerName, containerURL, err := GetTraefikConfiguration(container)
-			if err != nil {
-				continue
-			}
-			//if RuntimeConfig.Exposed == "true" {
-			//containerName, containerURL := GetExposedConfiguration(container)
-			//}
-
-			// check if lander.group is already present
-			if _, found := payload.Groups[container.Labels["lander.group"]]; found {
-				payload.Groups[container.Labels["lander.group"]] = append(payload.Groups[container.Labels["lander.group"]], Container{AppName: containerName, AppURL: containerURL})
-			} else {
-				payload.Groups[container.Labels["lander.group"]] = []Container{Container{AppName: containerName, AppURL: containerURL}}
-			}
-		}
-	}
-}
+// activeProviders holds the providers built from RuntimeConfig.Providers in main and is read
+// by RenderAndRespond when LANDER_WATCH is disabled.
+var activeProviders []Provider
 
-func GetTraefikConfiguration(container docker.APIContainers) (containerName string, containerURL string, err error) {
-	if RuntimeConfig.Traefik == "true" {
-		// extract strings for easier use
-		containerName := container.Labels["lander.name"]
-		delimiterPosition := strings.LastIndex(container.Labels["traefik.frontend.rule"], ":")
-		containerURL := container.Labels["traefik.frontend.rule"][delimiterPosition:]
-		// return extracted values
-		return containerName, containerURL, nil
-	} else {
-		err := errors.New("LANDER_TRAEFIK is set to false")
-		return "", "", err
-	}
-}
-
-// GetContainers
-func GetContainers(dockerSocket string) []docker.APIContainers {
-	// get new client
-	client, err := docker.NewClient(dockerSocket)
-	if err != nil {
-		log.Panic(err)
-	}
-
-	// get running containers
-	containers, err := client.ListContainers(docker.ListContainersOptions{All: true})
-	if err != nil {
-		log.Panic(err)
-	}
-
-	return containers
-}
+// payloadCache holds the last payload built by the configured providers and is only used in
+// LANDER_WATCH mode. payloadCacheMu guards it so RenderAndRespond can safely read it while
+// watchProviders rebuilds it in the background.
+var (
+	payloadCache   PayloadData
+	payloadCacheMu sync.RWMutex
+)
 
 // RenderAndRespond get's the metadata to render, renders and delivers the http GET response.
 func RenderAndRespond(w http.ResponseWriter, r *http.Request) {
@@ -104,10 +66,14 @@ func RenderAndRespond(w http.ResponseWriter, r *http.Request) {
 	// print request to log
 	log.Debug(r.RemoteAddr, " ", r.Method, " ", r.URL)
 
-	var payload = PayloadData{"", make(map[string][]Container)}
-	payload.Get(GetContainers(RuntimeConfig.Docker))
-
-	payload.Title = RuntimeConfig.Title
+	var payload PayloadData
+	if RuntimeConfig.Watch == "true" {
+		payloadCacheMu.RLock()
+		payload = payloadCache
+		payloadCacheMu.RUnlock()
+	} else {
+		payload = PayloadData{RuntimeConfig.Title, groupContainers(discoverAll(activeProviders))}
+	}
 
 	templ := template.Must(template.ParseFiles("template.html"))
 
@@ -122,12 +88,9 @@ func GetConfig() Config {
 	// create new variable of type Config
 	var config Config
 
-	// try to get the path to docker's socket and exit the application if not found
+	// the docker endpoint is only required when the docker provider is in use, so it's read
+	// as-is here; buildProviders is what fails fast if it's missing but needed
 	config.Docker = os.Getenv("LANDER_DOCKER")
-	if config.Docker == "" {
-		// throw a fatal-message into log and quit the application, since we can't do anything useful without a docker daemon to connect to
-		log.Fatal("environment variable LANDER_DOCKER not set! Can't start the server without a docker endpoint.")
-	}
 
 	// try to get the value of ENV "LANDER_TRAEFIK" and set a default value if not successful
 	config.Traefik = os.Getenv("LANDER_TRAEFIK")
@@ -164,6 +127,37 @@ func GetConfig() Config {
 		config.Hostname = ""
 	}
 
+	// try to get the value of ENV "LANDER_WATCH" and set a default value if not successful
+	config.Watch = os.Getenv("LANDER_WATCH")
+	if config.Watch == "" {
+		log.Info("environment variable LANDER_WATCH not set, assuming: \"false\"")
+		config.Watch = "false"
+	}
+
+	// try to get the value of ENV "LANDER_PROVIDERS" and set a default value if not successful
+	config.Providers = os.Getenv("LANDER_PROVIDERS")
+	if config.Providers == "" {
+		log.Info("environment variable LANDER_PROVIDERS not set, assuming: \"docker\"")
+		config.Providers = "docker"
+	}
+
+	// only required when the file provider is enabled, so it's read as-is here too
+	config.FilePath = os.Getenv("LANDER_FILE_PATH")
+
+	// CORS is entirely optional, so this is read as-is: an empty origin means no CORS headers
+	config.CORSOrigin = os.Getenv("LANDER_CORS_ORIGIN")
+
+	// TLS for the docker client is entirely optional, so these are read as-is without
+	// logging a default: an empty CA/cert/key simply means "plain, unencrypted client"
+	config.DockerTLSCA = os.Getenv("LANDER_DOCKER_TLS_CA")
+	config.DockerTLSCert = os.Getenv("LANDER_DOCKER_TLS_CERT")
+	config.DockerTLSKey = os.Getenv("LANDER_DOCKER_TLS_KEY")
+
+	config.DockerTLSInsecure = os.Getenv("LANDER_DOCKER_TLS_INSECURE")
+	if config.DockerTLSInsecure == "" {
+		config.DockerTLSInsecure = "false"
+	}
+
 	return config
 }
 
@@ -188,6 +182,7 @@ func initLogger() {
 func startHTTPListener() {
 	// register handle function for root context
 	http.HandleFunc("/", RenderAndRespond)
+	http.HandleFunc("/api/v1/groups", ServeGroupsJSON)
 
 	// start listener
 	log.Info("Starting Server on ", RuntimeConfig.Listen)
@@ -202,5 +197,15 @@ func main() {
 	// initialize logger and start listener
 	RuntimeConfig = GetConfig()
 	initLogger()
+
+	activeProviders = buildProviders(RuntimeConfig)
+
+	// in watch mode, keep the cached payload fresh in the background instead of
+	// re-running provider discovery on every request
+	if RuntimeConfig.Watch == "true" {
+		refreshPayloadCache(activeProviders)
+		go watchProviders(activeProviders)
+	}
+
 	startHTTPListener()
 }
@@ -0,0 +1,117 @@
+package main
+
+import (
+	"strings"
+
+	log "github.com/sirupsen/logrus"
+)
+
+// Provider is implemented by anything that can discover Containers to display. Each Container
+// carries its own Group, so a Provider's output can be merged with any other Provider's.
+type Provider interface {
+	Discover() ([]Container, error)
+}
+
+// Watchable is implemented by providers that can push updates as they happen instead of only
+// being polled. Providers for which "watching" makes no sense (e.g. FileProvider) simply don't
+// implement it.
+type Watchable interface {
+	Watch(updates chan<- []Container)
+}
+
+// buildProviders resolves LANDER_PROVIDERS into concrete Provider implementations.
+func buildProviders(config Config) []Provider {
+	var providers []Provider
+
+	for _, name := range strings.Split(config.Providers, ",") {
+		switch strings.TrimSpace(name) {
+		case "docker":
+			if config.Docker == "" {
+				log.Fatal("environment variable LANDER_DOCKER not set! Can't use the docker provider without a docker endpoint.")
+			}
+			providers = append(providers, DockerProvider{Endpoint: config.Docker})
+		case "kubernetes":
+			provider, err := NewKubernetesProvider()
+			if err != nil {
+				log.Error("failed to set up kubernetes provider: ", err)
+				continue
+			}
+			providers = append(providers, provider)
+		case "file":
+			if config.FilePath == "" {
+				log.Error("LANDER_PROVIDERS includes \"file\" but LANDER_FILE_PATH is not set, skipping")
+				continue
+			}
+			providers = append(providers, FileProvider{Path: config.FilePath})
+		case "":
+			// ignore stray separators, e.g. a trailing comma
+		default:
+			log.Warn("unknown provider in LANDER_PROVIDERS: ", name)
+		}
+	}
+
+	return providers
+}
+
+// discoverAll runs Discover on every provider and merges the results into one flat list. A
+// provider failing to discover is logged and skipped rather than failing the whole page.
+func discoverAll(providers []Provider) []Container {
+	var all []Container
+
+	for _, provider := range providers {
+		containers, err := provider.Discover()
+		if err != nil {
+			log.Error("provider discovery failed: ", err)
+			continue
+		}
+
+		all = append(all, containers...)
+	}
+
+	return all
+}
+
+// groupContainers buckets a flat list of Containers into the map[string][]Container shape
+// PayloadData expects, using each Container's Group field as the key.
+func groupContainers(containers []Container) map[string][]Container {
+	groups := make(map[string][]Container)
+
+	for _, container := range containers {
+		groups[container.Group] = append(groups[container.Group], container)
+	}
+
+	return groups
+}
+
+// refreshPayloadCache re-runs discovery on every provider and atomically replaces the cached
+// payload used by RenderAndRespond when LANDER_WATCH is enabled.
+func refreshPayloadCache(providers []Provider) {
+	payload := PayloadData{RuntimeConfig.Title, groupContainers(discoverAll(providers))}
+
+	payloadCacheMu.Lock()
+	payloadCache = payload
+	payloadCacheMu.Unlock()
+}
+
+// watchProviders keeps the payload cache in sync with any provider that supports Watch. Since
+// an update only tells us one provider changed, not what changed, it simply triggers a full
+// re-discovery across all providers.
+func watchProviders(providers []Provider) {
+	updates := make(chan []Container)
+
+	watching := false
+	for _, provider := range providers {
+		if watchable, ok := provider.(Watchable); ok {
+			watching = true
+			go watchable.Watch(updates)
+		}
+	}
+
+	if !watching {
+		return
+	}
+
+	for range updates {
+		refreshPayloadCache(providers)
+	}
+}
@@ -0,0 +1,164 @@
+package main
+
+import (
+	"errors"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/fsouza/go-dockerclient"
+)
+
+// routerRuleLabel matches a Traefik v2 router rule label, e.g. "traefik.http.routers.app.rule",
+// capturing the router name ("app").
+var routerRuleLabel = regexp.MustCompile(`^traefik\.http\.routers\.([^.]+)\.rule$`)
+
+// hostExprPattern and pathPrefixExprPattern pull the arguments out of the Host()/HostRegexp()
+// and PathPrefix() calls inside a router rule, e.g. "Host(`a.com`,`b.com`) && PathPrefix(`/api`)".
+var (
+	hostExprPattern       = regexp.MustCompile(`(?i)Host(?:Regexp)?\(([^)]*)\)`)
+	pathPrefixExprPattern = regexp.MustCompile(`(?i)PathPrefix\(([^)]*)\)`)
+)
+
+// GetTraefikConfiguration parses a container's Traefik v2 router labels and returns one
+// Container per router whose rule has a Host()/HostRegexp() expression (routers without one are
+// skipped), falling back to the legacy traefik.frontend.rule label when no v2 router rules are
+// present.
+func GetTraefikConfiguration(container docker.APIContainers) ([]Container, error) {
+	if RuntimeConfig.Traefik != "true" {
+		return nil, errors.New("LANDER_TRAEFIK is set to false")
+	}
+
+	if container.Labels["traefik.enable"] == "false" {
+		return nil, errors.New("traefik.enable is set to false")
+	}
+
+	routers := traefikRouterNames(container.Labels)
+	if len(routers) == 0 {
+		return legacyTraefikConfiguration(container)
+	}
+
+	landerName := container.Labels["lander.name"]
+	group := container.Labels["lander.group"]
+
+	var containers []Container
+	for _, router := range routers {
+		rule := container.Labels["traefik.http.routers."+router+".rule"]
+
+		host := parseTraefikHost(rule)
+		if host == "" {
+			continue
+		}
+
+		scheme := "http"
+		if container.Labels["traefik.http.routers."+router+".tls"] == "true" {
+			scheme = "https"
+		}
+
+		appName := landerName
+		if appName == "" {
+			appName = router
+		} else if len(routers) > 1 {
+			appName = landerName + " (" + router + ")"
+		}
+
+		containers = append(containers, Container{
+			AppName: appName,
+			AppURL:  scheme + "://" + host + parseTraefikPathPrefix(rule),
+			Group:   group,
+		})
+	}
+
+	if len(containers) == 0 {
+		return nil, errors.New("no usable traefik router rule found")
+	}
+
+	return containers, nil
+}
+
+// legacyTraefikConfiguration handles the pre-v2 "traefik.frontend.rule" label, which lander has
+// always read by taking everything after the last colon as the URL.
+func legacyTraefikConfiguration(container docker.APIContainers) ([]Container, error) {
+	rule, found := container.Labels["traefik.frontend.rule"]
+	if !found {
+		return nil, errors.New("no traefik router labels found")
+	}
+
+	delimiterPosition := strings.LastIndex(rule, ":")
+	if delimiterPosition == -1 {
+		return nil, errors.New("malformed traefik.frontend.rule label")
+	}
+
+	containerName := container.Labels["lander.name"]
+	containerURL := rule[delimiterPosition+1:]
+	group := container.Labels["lander.group"]
+
+	return []Container{{AppName: containerName, AppURL: containerURL, Group: group}}, nil
+}
+
+// traefikRouterNames returns the distinct router names found in a container's labels, e.g.
+// {"traefik.http.routers.app.rule": "..."} yields []string{"app"}.
+func traefikRouterNames(labels map[string]string) []string {
+	seen := make(map[string]bool)
+	var names []string
+
+	for key := range labels {
+		match := routerRuleLabel.FindStringSubmatch(key)
+		if match == nil || seen[match[1]] {
+			continue
+		}
+
+		seen[match[1]] = true
+		names = append(names, match[1])
+	}
+
+	sort.Strings(names)
+
+	return names
+}
+
+// parseTraefikHost extracts the first host from a router rule's Host()/HostRegexp() expression.
+func parseTraefikHost(rule string) string {
+	match := hostExprPattern.FindStringSubmatch(rule)
+	if match == nil {
+		return ""
+	}
+
+	args := splitTraefikArgs(match[1])
+	if len(args) == 0 {
+		return ""
+	}
+
+	return args[0]
+}
+
+// parseTraefikPathPrefix extracts the first path from a router rule's PathPrefix() expression,
+// if any.
+func parseTraefikPathPrefix(rule string) string {
+	match := pathPrefixExprPattern.FindStringSubmatch(rule)
+	if match == nil {
+		return ""
+	}
+
+	args := splitTraefikArgs(match[1])
+	if len(args) == 0 {
+		return ""
+	}
+
+	return args[0]
+}
+
+// splitTraefikArgs splits a comma-separated, backtick-quoted argument list such as
+// "`a.example.com`,`b.example.com`" into []string{"a.example.com", "b.example.com"}.
+func splitTraefikArgs(raw string) []string {
+	var args []string
+
+	for _, part := range strings.Split(raw, ",") {
+		part = strings.Trim(strings.TrimSpace(part), "`")
+		if part != "" {
+			args = append(args, part)
+		}
+	}
+
+	return args
+}